@@ -0,0 +1,98 @@
+package devices
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Autotune runs the Ziegler-Nichols relay method against readings arriving on
+// o.Readings: it bangs Output between +-relayAmplitude around Setpoint, measures
+// the resulting oscillation's ultimate period Tu and amplitude, and sets
+// Controller's Kp/Ki/Kd from the classic relay formulas (Kp=0.6*Ku,
+// Ki=1.2*Ku/Tu, Kd=0.075*Ku*Tu). It returns once cycles full oscillations have
+// been observed, or the Readings channel is closed.
+func (o *OutputControl) Autotune(relayAmplitude float64, cycles int) {
+	var (
+		high, low  float64
+		lastCross  time.Time
+		periods    []float64
+		aboveSetpt bool
+		haveCross  bool
+		crossings  int
+		first      = true
+		u          = relayAmplitude
+	)
+
+	for reading := range o.Readings {
+		if first {
+			aboveSetpt = reading.Value > o.Setpoint
+			high, low = reading.Value, reading.Value
+			first = false
+		}
+
+		if reading.Value > high {
+			high = reading.Value
+		}
+		if reading.Value < low {
+			low = reading.Value
+		}
+
+		nowAbove := reading.Value > o.Setpoint
+		if nowAbove != aboveSetpt {
+			u = -u
+			aboveSetpt = nowAbove
+			crossings++
+
+			// A full oscillation is two crossings (up, then down) apart, so only
+			// measure every other crossing - otherwise periods ends up full of
+			// half-periods and tu comes out at roughly half the true Tu.
+			if crossings%2 == 0 {
+				if haveCross {
+					periods = append(periods, reading.Timestamp.Sub(lastCross).Seconds())
+				}
+				lastCross = reading.Timestamp
+				haveCross = true
+
+				if len(periods) >= cycles {
+					if err := o.Output.Apply(0, reading.Timestamp); err != nil {
+						o.sendError(err)
+					}
+					break
+				}
+			}
+		}
+
+		if err := o.Output.Apply(u, reading.Timestamp); err != nil {
+			o.sendError(err)
+		}
+	}
+
+	tu := average(periods)
+	amplitude := (high - low) / 2
+	if tu == 0 || amplitude == 0 {
+		fmt.Println("Autotune: not enough oscillation observed, leaving gains unchanged")
+		return
+	}
+
+	ku := (4 * relayAmplitude) / (math.Pi * amplitude)
+
+	o.Controller.Kp = 0.6 * ku
+	o.Controller.Ki = 1.2 * ku / tu
+	o.Controller.Kd = 0.075 * ku * tu
+	o.Controller.Reset()
+
+	fmt.Printf("Autotune complete: Ku=%.4f Tu=%.2fs -> Kp=%.4f Ki=%.4f Kd=%.4f\n",
+		ku, tu, o.Controller.Kp, o.Controller.Ki, o.Controller.Kd)
+}
+
+func average(vals []float64) float64 {
+	if len(vals) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range vals {
+		sum += v
+	}
+	return sum / float64(len(vals))
+}
@@ -0,0 +1,82 @@
+package devices
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestAutotuneMeasuresFullPeriodNotHalfPeriod feeds a synthetic oscillation with
+// a known period (2s peak-to-peak) and amplitude (±2 around the setpoint) and
+// checks the resulting gains are derived from that true period - not half of it,
+// which is what you'd get if every setpoint crossing were mistaken for a full
+// oscillation.
+func TestAutotuneMeasuresFullPeriodNotHalfPeriod(t *testing.T) {
+	readings := make(chan Reading, 8)
+	start := time.Now()
+	for i, v := range []float64{12, 8, 12, 8, 12, 8, 12} {
+		readings <- Reading{Value: v, Timestamp: start.Add(time.Duration(i) * time.Second)}
+	}
+	close(readings)
+
+	o := &OutputControl{
+		Readings:   readings,
+		Output:     &TimeProportionalOutput{Window: time.Second},
+		Controller: &PIDController{},
+		Setpoint:   10,
+	}
+
+	const relayAmplitude = 1.0
+	o.Autotune(relayAmplitude, 2)
+
+	const wantTu = 2.0 // peak-to-peak spacing in the readings above
+	wantKu := (4 * relayAmplitude) / (math.Pi * 2)
+	wantKp := 0.6 * wantKu
+	wantKi := 1.2 * wantKu / wantTu
+	wantKd := 0.075 * wantKu * wantTu
+
+	if math.Abs(o.Controller.Kp-wantKp) > 1e-9 {
+		t.Fatalf("Kp = %v, want %v", o.Controller.Kp, wantKp)
+	}
+	if math.Abs(o.Controller.Ki-wantKi) > 1e-9 {
+		t.Fatalf("Ki = %v, want %v (a half-period bug would double this)", o.Controller.Ki, wantKi)
+	}
+	if math.Abs(o.Controller.Kd-wantKd) > 1e-9 {
+		t.Fatalf("Kd = %v, want %v (a half-period bug would halve this)", o.Controller.Kd, wantKd)
+	}
+}
+
+// TestAutotuneStopsAfterRequestedFullCycles checks Autotune consumes readings
+// through the crossing that completes the requested number of full
+// oscillations, not the crossing that completes half that many.
+func TestAutotuneStopsAfterRequestedFullCycles(t *testing.T) {
+	readings := make(chan Reading, 16)
+	start := time.Now()
+	values := []float64{12, 8, 12, 8, 12, 8, 12, 8, 12, 8, 12}
+	for i, v := range values {
+		readings <- Reading{Value: v, Timestamp: start.Add(time.Duration(i) * time.Second)}
+	}
+
+	o := &OutputControl{
+		Readings:   readings,
+		Output:     &TimeProportionalOutput{Window: time.Second},
+		Controller: &PIDController{},
+		Setpoint:   10,
+	}
+
+	o.Autotune(1, 1)
+
+	// One full cycle (two crossings, peak->trough->peak) completes at t=4s.
+	// Readings after that must be left on the channel, unread.
+	select {
+	case r, ok := <-readings:
+		if !ok {
+			t.Fatal("Readings closed early; Autotune drained more than one full cycle's worth")
+		}
+		if r.Timestamp.Sub(start) != 5*time.Second {
+			t.Fatalf("first unread reading at t=%v, want t=5s (right after the first full cycle)", r.Timestamp.Sub(start))
+		}
+	default:
+		t.Fatal("expected unread readings left on the channel after one full cycle")
+	}
+}
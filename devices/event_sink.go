@@ -0,0 +1,9 @@
+package devices
+
+// EventSink publishes Events emitted by an OutputControl to an external system.
+// OutputControl only depends on this interface, so the devices package never
+// imports a specific transport (NATS, MQTT, ...) directly; a no-op sink or a test
+// spy satisfies it just as well.
+type EventSink interface {
+	Publish(evt Event) error
+}
@@ -0,0 +1,82 @@
+package devices
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/warthog618/gpiod"
+)
+
+// GpiodDriver is a PinDriver backed by the Linux gpiod character-device API, the
+// modern replacement for the deprecated sysfs GPIO interface. The pin name
+// passed to Open is the line offset on Chip, e.g. "17" on chip "gpiochip0".
+type GpiodDriver struct {
+	Chip string
+
+	line  *gpiod.Line
+	edges chan bool
+}
+
+// Open requests the line at the numeric offset name on Chip, watching both edges.
+func (g *GpiodDriver) Open(name string) error {
+	offset, err := strconv.Atoi(name)
+	if err != nil {
+		return fmt.Errorf("gpiod: line identifier must be a numeric offset, got %q: %w", name, err)
+	}
+
+	g.edges = make(chan bool, 1)
+	line, err := gpiod.RequestLine(g.Chip, offset,
+		gpiod.WithBothEdges,
+		gpiod.WithEventHandler(func(evt gpiod.LineEvent) {
+			g.edges <- evt.Type == gpiod.LineEventRisingEdge
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("gpiod: requesting line %d on %s: %w", offset, g.Chip, err)
+	}
+
+	g.line = line
+	return nil
+}
+
+// SetOutput reconfigures the line as an output and drives it high or low.
+func (g *GpiodDriver) SetOutput(high bool) error {
+	value := 0
+	if high {
+		value = 1
+	}
+	return g.line.Reconfigure(gpiod.AsOutput(value))
+}
+
+// SetInput reconfigures the line as an input.
+func (g *GpiodDriver) SetInput() error {
+	return g.line.Reconfigure(gpiod.AsInput)
+}
+
+// WatchEdge waits for the event handler registered in Open to observe an edge.
+func (g *GpiodDriver) WatchEdge(timeout time.Duration) (bool, error) {
+	select {
+	case level := <-g.edges:
+		return level, nil
+	case <-time.After(timeout):
+		return g.Read()
+	}
+}
+
+// Read reports the line's current value.
+func (g *GpiodDriver) Read() (bool, error) {
+	value, err := g.line.Value()
+	if err != nil {
+		return false, err
+	}
+	return value != 0, nil
+}
+
+// Close releases the requested line.
+func (g *GpiodDriver) Close() error {
+	if g.line == nil {
+		return nil
+	}
+	return g.line.Close()
+}
@@ -0,0 +1,151 @@
+package devices
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// defaultDebounce is how long a pin's level must hold steady before a watch loop
+// trusts it enough to emit an Event.
+const defaultDebounce = 20 * time.Millisecond
+
+// pollInterval is the sampling cadence used when the underlying driver doesn't
+// support edge notifications.
+const pollInterval = 5 * time.Millisecond
+
+// EventType identifies the kind of transition an InPin observed.
+type EventType string
+
+const (
+	// EventOpened is published when a monitored sensor settles high (e.g. a door/lid opening).
+	EventOpened EventType = "opened"
+	// EventClosed is published when a monitored sensor settles low (e.g. a door/lid closing).
+	EventClosed EventType = "closed"
+)
+
+// Event describes a state transition published by an InPin or an OutputControl.
+// It carries a timestamp and the pin identifier so downstream code (logging,
+// publishing) can correlate it against other readings such as temperature. Value
+// holds the associated reading for EventTemperature events and is zero otherwise.
+type Event struct {
+	Timestamp     time.Time
+	PinIdentifier string
+	Type          EventType
+	Value         float64
+}
+
+// InPin represents a stored input pin (e.g. a fermenter door/lid switch or a float
+// switch) with a friendly name, mirroring OutPin.
+type InPin struct {
+	gorm.Model
+
+	Identifier   string
+	FriendlyName string
+	Driver       PinDriver     `gorm:"-"`
+	Debounce     time.Duration `gorm:"-"`
+
+	events chan Event
+	errors chan error
+}
+
+func (ip *InPin) reset() error {
+	if ip.Identifier == "" {
+		return nil
+	}
+
+	if ip.Driver == nil {
+		ip.Driver = &PeriphDriver{}
+	}
+	if err := ip.Driver.Open(ip.Identifier); err != nil {
+		return fmt.Errorf("opening %s: %w", ip.Identifier, err)
+	}
+	if err := ip.Driver.SetInput(); err != nil {
+		return fmt.Errorf("configuring %s as input: %w", ip.Identifier, err)
+	}
+
+	if ip.Debounce == 0 {
+		ip.Debounce = defaultDebounce
+	}
+	return nil
+}
+
+// Events returns the channel Events are published on once Watch has been called.
+func (ip *InPin) Events() <-chan Event {
+	return ip.events
+}
+
+// Errors returns the channel driver failures are published on once Watch has
+// been called.
+func (ip *InPin) Errors() <-chan error {
+	return ip.errors
+}
+
+// Watch starts monitoring the pin for edges and publishes debounced Events on the
+// channel returned by Events until quit is closed. If the underlying driver
+// doesn't support edge notifications, WatchEdge just waits out pollInterval and
+// the loop falls back to polling at that cadence.
+func (ip *InPin) Watch(quit <-chan struct{}) {
+	ip.events = make(chan Event, 8)
+	ip.errors = make(chan error, 8)
+
+	if err := ip.reset(); err != nil {
+		ip.errors <- err
+		return
+	}
+
+	go ip.watchLoop(quit)
+}
+
+func (ip *InPin) watchLoop(quit <-chan struct{}) {
+	last, err := ip.Driver.Read()
+	if err != nil {
+		ip.errors <- err
+		return
+	}
+
+	for {
+		select {
+		case <-quit:
+			return
+		default:
+		}
+
+		if _, err := ip.Driver.WatchEdge(pollInterval); err != nil {
+			ip.errors <- err
+			continue
+		}
+
+		level, ok, err := ip.debouncedLevel()
+		if err != nil {
+			ip.errors <- err
+			continue
+		}
+		if !ok || level == last {
+			continue
+		}
+		last = level
+
+		evt := Event{Timestamp: time.Now(), PinIdentifier: ip.Identifier, Type: EventClosed}
+		if level {
+			evt.Type = EventOpened
+		}
+		ip.events <- evt
+	}
+}
+
+// debouncedLevel samples the pin twice, Debounce apart, and reports the level only
+// if it held steady across the window.
+func (ip *InPin) debouncedLevel() (bool, bool, error) {
+	level, err := ip.Driver.Read()
+	if err != nil {
+		return false, false, err
+	}
+	time.Sleep(ip.Debounce)
+	after, err := ip.Driver.Read()
+	if err != nil {
+		return false, false, err
+	}
+	return level, after == level, nil
+}
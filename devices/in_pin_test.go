@@ -0,0 +1,71 @@
+package devices
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInPinWatchEmitsDebouncedEvents(t *testing.T) {
+	edges := make(chan bool, 1)
+	ip := &InPin{
+		Identifier: "door",
+		Driver:     &MockDriver{Edges: edges},
+		Debounce:   time.Millisecond,
+	}
+
+	quit := make(chan struct{})
+	defer close(quit)
+
+	ip.Watch(quit)
+
+	edges <- true
+	select {
+	case evt := <-ip.Events():
+		if evt.Type != EventOpened {
+			t.Fatalf("Type = %v, want EventOpened", evt.Type)
+		}
+		if evt.PinIdentifier != "door" {
+			t.Fatalf("PinIdentifier = %q, want %q", evt.PinIdentifier, "door")
+		}
+	case err := <-ip.Errors():
+		t.Fatalf("unexpected error: %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for EventOpened")
+	}
+
+	edges <- false
+	select {
+	case evt := <-ip.Events():
+		if evt.Type != EventClosed {
+			t.Fatalf("Type = %v, want EventClosed", evt.Type)
+		}
+	case err := <-ip.Errors():
+		t.Fatalf("unexpected error: %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for EventClosed")
+	}
+}
+
+// flippingDriver reports a different level on every successive Read, simulating
+// a pin whose level isn't yet stable across a debounce window.
+type flippingDriver struct {
+	MockDriver
+	reads int
+}
+
+func (f *flippingDriver) Read() (bool, error) {
+	f.reads++
+	return f.reads > 1, nil
+}
+
+func TestInPinDebouncedLevelRejectsUnstableReads(t *testing.T) {
+	ip := &InPin{Identifier: "door", Driver: &flippingDriver{}, Debounce: time.Millisecond}
+
+	_, ok, err := ip.debouncedLevel()
+	if err != nil {
+		t.Fatalf("debouncedLevel() error = %v", err)
+	}
+	if ok {
+		t.Fatal("debouncedLevel() reported stable despite the level flipping mid-window")
+	}
+}
@@ -0,0 +1,87 @@
+package devices
+
+import "time"
+
+// AuxOutput is a secondary output (fan, light) flipped by the same door/lid events
+// that drive an OutputControl's interlock, rather than by its duty cycle.
+type AuxOutput struct {
+	Output *OutPin
+	// ActiveOnOpen selects which sensor state energises Output: true for an output
+	// that should be on while the door is open (e.g. a chamber light), false for one
+	// that should be on while it's closed (e.g. a fan that must stop while accessed).
+	ActiveOnOpen bool
+}
+
+func (a *AuxOutput) apply(open bool) error {
+	if a == nil || a.Output == nil {
+		return nil
+	}
+
+	if open == a.ActiveOnOpen {
+		return a.Output.on()
+	}
+	return a.Output.off()
+}
+
+// runInterlock consumes DoorSensor events and pauses/resumes CalculateOutput:
+// it pauses immediately on open (CalculateOutput then forces heat/cool off on each
+// reading while paused) and resumes SettleDelay after close.
+// It also drives AuxOutputs and forwards every event on o.Events for higher layers.
+func (o *OutputControl) runInterlock(quit <-chan struct{}) {
+	var resumeTimer *time.Timer
+
+	for {
+		select {
+		case evt, ok := <-o.DoorSensor.Events():
+			if !ok {
+				return
+			}
+
+			open := evt.Type == EventOpened
+			for _, aux := range o.AuxOutputs {
+				if err := aux.apply(open); err != nil {
+					o.sendError(err)
+				}
+			}
+
+			if open {
+				if resumeTimer != nil {
+					resumeTimer.Stop()
+				}
+				o.setPaused(true)
+				o.publish(Event{Timestamp: evt.Timestamp, Type: EventPaused})
+			} else if o.SettleDelay <= 0 {
+				o.setPaused(false)
+				o.Controller.Reset()
+				o.publish(Event{Timestamp: evt.Timestamp, Type: EventResumed})
+			} else {
+				resumeTimer = time.AfterFunc(o.SettleDelay, func() {
+					o.setPaused(false)
+					o.Controller.Reset()
+					o.publish(Event{Timestamp: time.Now(), Type: EventResumed})
+				})
+			}
+
+			o.publish(evt)
+		case err, ok := <-o.DoorSensor.Errors():
+			if !ok {
+				return
+			}
+			o.sendError(err)
+		case <-quit:
+			if resumeTimer != nil {
+				resumeTimer.Stop()
+			}
+			return
+		}
+	}
+}
+
+// sendError forwards err to o.Errors without blocking the caller if nobody is
+// draining it.
+func (o *OutputControl) sendError(err error) {
+	select {
+	case o.Errors <- err:
+	default:
+	}
+}
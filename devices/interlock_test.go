@@ -0,0 +1,141 @@
+package devices
+
+import (
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it's true or the timeout elapses, failing the test
+// otherwise. The interlock runs on its own goroutine, so tests observe it async.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("condition not met before timeout")
+}
+
+func collectEventTypes(t *testing.T, events <-chan Event, n int) []EventType {
+	t.Helper()
+	types := make([]EventType, 0, n)
+	for i := 0; i < n; i++ {
+		select {
+		case evt := <-events:
+			types = append(types, evt.Type)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for event %d/%d", i+1, n)
+		}
+	}
+	return types
+}
+
+func containsEventType(types []EventType, want EventType) bool {
+	for _, got := range types {
+		if got == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRunInterlockPausesOnOpenAndDrivesAux(t *testing.T) {
+	edges := make(chan bool, 1)
+	light := newMockOutPin()
+
+	o := &OutputControl{
+		HeatOutput: newMockOutPin(),
+		CoolOutput: newMockOutPin(),
+		DoorSensor: &InPin{Identifier: "door", Driver: &MockDriver{Edges: edges}, Debounce: time.Millisecond},
+		AuxOutputs: []*AuxOutput{{Output: light, ActiveOnOpen: true}},
+		Events:     make(chan Event, 8),
+		Errors:     make(chan error, 8),
+	}
+
+	quit := make(chan struct{})
+	defer close(quit)
+	o.DoorSensor.Watch(quit)
+	go o.runInterlock(quit)
+
+	edges <- true
+
+	waitFor(t, o.isPaused)
+	waitFor(t, func() bool { return light.onTime != nil })
+
+	types := collectEventTypes(t, o.Events, 2)
+	if !containsEventType(types, EventPaused) {
+		t.Fatalf("events %v missing EventPaused", types)
+	}
+	if !containsEventType(types, EventOpened) {
+		t.Fatalf("events %v missing EventOpened", types)
+	}
+}
+
+func TestRunInterlockResumesImmediatelyWithoutSettleDelay(t *testing.T) {
+	edges := make(chan bool, 1)
+	light := newMockOutPin()
+
+	o := &OutputControl{
+		HeatOutput: newMockOutPin(),
+		CoolOutput: newMockOutPin(),
+		DoorSensor: &InPin{Identifier: "door", Driver: &MockDriver{Edges: edges}, Debounce: time.Millisecond},
+		AuxOutputs: []*AuxOutput{{Output: light, ActiveOnOpen: true}},
+		Events:     make(chan Event, 8),
+		Errors:     make(chan error, 8),
+	}
+
+	quit := make(chan struct{})
+	defer close(quit)
+	o.DoorSensor.Watch(quit)
+	go o.runInterlock(quit)
+
+	edges <- true
+	waitFor(t, o.isPaused)
+	collectEventTypes(t, o.Events, 2) // drain EventPaused + EventOpened
+
+	edges <- false
+	waitFor(t, func() bool { return !o.isPaused() })
+	waitFor(t, func() bool { return light.onTime == nil })
+
+	types := collectEventTypes(t, o.Events, 2)
+	if !containsEventType(types, EventResumed) {
+		t.Fatalf("events %v missing EventResumed", types)
+	}
+	if !containsEventType(types, EventClosed) {
+		t.Fatalf("events %v missing EventClosed", types)
+	}
+}
+
+func TestRunInterlockRespectsSettleDelay(t *testing.T) {
+	edges := make(chan bool, 1)
+
+	o := &OutputControl{
+		HeatOutput:  newMockOutPin(),
+		CoolOutput:  newMockOutPin(),
+		DoorSensor:  &InPin{Identifier: "door", Driver: &MockDriver{Edges: edges}, Debounce: time.Millisecond},
+		SettleDelay: 50 * time.Millisecond,
+		Events:      make(chan Event, 8),
+		Errors:      make(chan error, 8),
+	}
+
+	quit := make(chan struct{})
+	defer close(quit)
+	o.DoorSensor.Watch(quit)
+	go o.runInterlock(quit)
+
+	edges <- true
+	waitFor(t, o.isPaused)
+	collectEventTypes(t, o.Events, 2)
+
+	edges <- false
+	collectEventTypes(t, o.Events, 1) // EventClosed, published immediately
+
+	if !o.isPaused() {
+		t.Fatal("controller resumed before SettleDelay elapsed")
+	}
+
+	waitFor(t, func() bool { return !o.isPaused() })
+}
@@ -0,0 +1,68 @@
+package devices
+
+import "time"
+
+// Transition records a single recorded state change on a MockDriver, for
+// deterministic assertions in tests.
+type Transition struct {
+	High bool
+	At   time.Time
+}
+
+// MockDriver is a PinDriver that records every SetOutput transition instead of
+// touching real hardware, so duty-cycle and debounce logic can be verified
+// deterministically off-Pi. Edges, if set, feeds WatchEdge for InPin tests: each
+// value sent on it simulates an edge firing with that level.
+type MockDriver struct {
+	Name        string
+	Transitions []Transition
+	Edges       chan bool
+
+	level  bool
+	closed bool
+}
+
+// Open records name; MockDriver never fails to open.
+func (m *MockDriver) Open(name string) error {
+	m.Name = name
+	return nil
+}
+
+// SetOutput records the transition and updates the current level.
+func (m *MockDriver) SetOutput(high bool) error {
+	m.level = high
+	m.Transitions = append(m.Transitions, Transition{High: high, At: time.Now()})
+	return nil
+}
+
+// SetInput is a no-op; MockDriver has no separate input/output mode.
+func (m *MockDriver) SetInput() error {
+	return nil
+}
+
+// WatchEdge returns the next value sent on Edges, or the current level after
+// timeout if Edges is nil or empty.
+func (m *MockDriver) WatchEdge(timeout time.Duration) (bool, error) {
+	if m.Edges == nil {
+		time.Sleep(timeout)
+		return m.level, nil
+	}
+	select {
+	case level := <-m.Edges:
+		m.level = level
+		return level, nil
+	case <-time.After(timeout):
+		return m.level, nil
+	}
+}
+
+// Read reports the current level.
+func (m *MockDriver) Read() (bool, error) {
+	return m.level, nil
+}
+
+// Close marks the driver closed.
+func (m *MockDriver) Close() error {
+	m.closed = true
+	return nil
+}
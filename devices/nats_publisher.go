@@ -0,0 +1,156 @@
+package devices
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSPublisher is an EventSink that serialises Events as JSON and publishes them
+// to NATS JetStream subjects, one per event category, so external dashboards,
+// Grafana ingest, or other Elsinore nodes can consume them.
+type NATSPublisher struct {
+	// StateSubject/EventSubject/TempSubject route heat/cool/pause-resume, door
+	// sensor and temperature events respectively, e.g. "elsinore.state".
+	StateSubject string
+	EventSubject string
+	TempSubject  string
+
+	// BufferSize bounds the in-memory queue Publish falls back to while the
+	// connection is down, so a broker outage can't stall the control loop.
+	BufferSize int
+
+	conn *nats.Conn
+	js   nats.JetStreamContext
+
+	mu       sync.Mutex
+	buffer   []Event
+	draining bool
+}
+
+// Connect dials url with reconnection/backoff enabled and obtains a JetStream
+// context. Events buffered while disconnected are flushed once connected.
+func (n *NATSPublisher) Connect(url string) error {
+	conn, err := nats.Connect(url,
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(time.Second),
+		nats.ReconnectJitter(100*time.Millisecond, time.Second),
+		nats.ReconnectHandler(func(*nats.Conn) { go n.drain() }),
+	)
+	if err != nil {
+		return fmt.Errorf("nats: connecting to %s: %w", url, err)
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		return fmt.Errorf("nats: obtaining JetStream context: %w", err)
+	}
+
+	n.conn = conn
+	n.js = js
+
+	if n.BufferSize <= 0 {
+		n.BufferSize = 256
+	}
+
+	go n.drain()
+	return nil
+}
+
+// Publish serialises evt as JSON and publishes it to the subject for its
+// category. If the connection is down, evt is queued (dropping the oldest entry
+// once BufferSize is reached) and flushed on reconnect, so a broker outage never
+// blocks the caller - typically an OutputControl's control loop.
+func (n *NATSPublisher) Publish(evt Event) error {
+	if n.conn == nil || !n.conn.IsConnected() {
+		n.enqueue(evt)
+		return nil
+	}
+
+	return n.publish(evt)
+}
+
+func (n *NATSPublisher) publish(evt Event) error {
+	subject := n.subject(evt.Type)
+
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("nats: marshalling event: %w", err)
+	}
+
+	if _, err := n.js.Publish(subject, payload); err != nil {
+		n.enqueue(evt)
+		return fmt.Errorf("nats: publishing to %s: %w", subject, err)
+	}
+	return nil
+}
+
+func (n *NATSPublisher) subject(t EventType) string {
+	switch t {
+	case EventTemperature:
+		return n.TempSubject
+	case EventOpened, EventClosed:
+		return n.EventSubject
+	default:
+		return n.StateSubject
+	}
+}
+
+func (n *NATSPublisher) enqueue(evt Event) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.BufferSize <= 0 {
+		n.BufferSize = 256
+	}
+	if len(n.buffer) >= n.BufferSize {
+		n.buffer = n.buffer[1:]
+	}
+	n.buffer = append(n.buffer, evt)
+}
+
+// drain flushes any buffered Events now that the connection is back up. It's
+// invoked from the reconnect handler, and is safe to call concurrently with
+// itself.
+func (n *NATSPublisher) drain() {
+	n.mu.Lock()
+	if n.draining {
+		n.mu.Unlock()
+		return
+	}
+	n.draining = true
+	n.mu.Unlock()
+
+	defer func() {
+		n.mu.Lock()
+		n.draining = false
+		n.mu.Unlock()
+	}()
+
+	for {
+		n.mu.Lock()
+		if len(n.buffer) == 0 || n.conn == nil || !n.conn.IsConnected() {
+			n.mu.Unlock()
+			return
+		}
+		evt := n.buffer[0]
+		n.buffer = n.buffer[1:]
+		n.mu.Unlock()
+
+		if err := n.publish(evt); err != nil {
+			n.enqueue(evt)
+			return
+		}
+	}
+}
+
+// Close drains any buffered Events, then closes the underlying connection.
+func (n *NATSPublisher) Close() {
+	n.drain()
+	if n.conn != nil {
+		n.conn.Close()
+	}
+}
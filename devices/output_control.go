@@ -3,24 +3,107 @@ package devices
 import (
 	"context"
 	"fmt"
-	"log"
-	"math"
+	"sync"
 	"time"
 
 	"gorm.io/gorm"
-	"periph.io/x/periph/conn/gpio"
-	"periph.io/x/periph/conn/gpio/gpioreg"
 )
 
 var Context, CancelFunc = context.WithCancel(context.Background())
 
+const (
+	// EventHeatOn/EventHeatOff/EventCoolOn/EventCoolOff are published by
+	// CalculateOutput whenever the time-proportional window turns an output on or off.
+	EventHeatOn  EventType = "heat-on"
+	EventHeatOff EventType = "heat-off"
+	EventCoolOn  EventType = "cool-on"
+	EventCoolOff EventType = "cool-off"
+	// EventPaused/EventResumed are published by the door interlock.
+	EventPaused  EventType = "paused"
+	EventResumed EventType = "resumed"
+	// EventTemperature is published for every Reading CalculateOutput processes; Value
+	// holds the reading.
+	EventTemperature EventType = "temperature"
+)
+
+// Reading is a single temperature sample fed to an OutputControl's controller.
+type Reading struct {
+	Value     float64
+	Timestamp time.Time
+}
+
 // OutputControl is a basic struct to handle heating outputs with a duty cyclke
 type OutputControl struct {
 	gorm.Model
 	HeatOutput *OutPin
 	CoolOutput *OutPin
-	DutyCycle  int64 `gorm:"-"`
-	CycleTime  int64 `gorm:"-"`
+
+	// Controller computes the bipolar [-1, 1] output from Setpoint and each Reading;
+	// Output then turns HeatOutput/CoolOutput on and off to realise it.
+	Controller *PIDController
+	Output     *TimeProportionalOutput
+	Setpoint   float64 `gorm:"-"`
+
+	// HeatPWM/CoolPWM, when set, drive that side of the controller via hardware PWM
+	// instead of the time-proportional window - for DC fans, pumps, or PWM-capable
+	// SSRs. If the configured pin turns out not to support hardware PWM, Reset logs
+	// it and falls back to HeatOutput/CoolOutput's software time-proportional control.
+	HeatPWM *PWMPin
+	CoolPWM *PWMPin
+
+	// Readings is the channel RunControl consumes temperature readings from.
+	Readings chan Reading `gorm:"-"`
+
+	// DoorSensor, when set, interlocks this controller: CalculateOutput is forced
+	// off for as long as the door reads open, and resumes SettleDelay after it closes.
+	DoorSensor  *InPin
+	SettleDelay time.Duration `gorm:"-"`
+	// AuxOutputs are flipped alongside the interlock, e.g. a chamber light or a fan
+	// that must not run while the chamber is being accessed.
+	AuxOutputs []*AuxOutput
+
+	// Events carries every state transition (heat/cool on/off, pause/resume,
+	// temperature readings, sensor events) for higher layers to consume.
+	Events chan Event `gorm:"-"`
+	// Errors carries pin driver failures from Reset/CalculateOutput so callers
+	// don't have to rely on a log.Fatal crashing the process.
+	Errors chan error `gorm:"-"`
+	// Sink, when set, additionally publishes every Event to an external system
+	// (NATS, MQTT, ...) without the devices package importing a specific transport.
+	Sink EventSink `gorm:"-"`
+
+	pausedMu sync.Mutex
+	paused   bool
+}
+
+// setPaused updates the interlock's pause state. It's called from runInterlock's
+// goroutine and the resume timer it schedules, while CalculateOutput reads it
+// from RunControl's goroutine, so access is guarded by pausedMu.
+func (o *OutputControl) setPaused(paused bool) {
+	o.pausedMu.Lock()
+	o.paused = paused
+	o.pausedMu.Unlock()
+}
+
+func (o *OutputControl) isPaused() bool {
+	o.pausedMu.Lock()
+	defer o.pausedMu.Unlock()
+	return o.paused
+}
+
+// publish forwards evt on Events (without blocking if nobody is draining it) and,
+// if Sink is configured, to the external sink.
+func (o *OutputControl) publish(evt Event) {
+	select {
+	case o.Events <- evt:
+	default:
+	}
+
+	if o.Sink != nil {
+		if err := o.Sink.Publish(evt); err != nil {
+			o.sendError(err)
+		}
+	}
 }
 
 // OutPin represents a stored output pin with a friendly name
@@ -29,114 +112,170 @@ type OutPin struct {
 
 	Identifier   string
 	FriendlyName string
-	PinIO        gpio.PinIO `gorm:"-"`
+	Driver       PinDriver `gorm:"-"`
 	onTime       *time.Time
 	offTime      *time.Time
 }
 
-func (op *OutPin) off() {
+func (op *OutPin) off() error {
 	if op == nil {
-		return
+		return nil
 	}
 
 	if op.offTime != nil {
-		return
+		return nil
 	}
 
-	if err := op.PinIO.Out(gpio.Low); err != nil {
-		log.Fatal(err)
+	if err := op.Driver.SetOutput(false); err != nil {
+		return fmt.Errorf("turning %s off: %w", op.Identifier, err)
 	}
 	curTime := time.Now()
 	op.offTime = &curTime
 	op.onTime = nil
+	return nil
 }
 
-func (op *OutPin) on() {
+func (op *OutPin) on() error {
 	if op == nil {
-		return
+		return nil
 	}
 
 	if op.onTime != nil {
-		return
+		return nil
 	}
-	if err := op.PinIO.Out(gpio.High); err != nil {
-		log.Fatal(err)
+	if err := op.Driver.SetOutput(true); err != nil {
+		return fmt.Errorf("turning %s on: %w", op.Identifier, err)
 	}
 	curTime := time.Now()
 	op.offTime = nil
 	op.onTime = &curTime
+	return nil
 }
 
-func (op *OutPin) reset() {
+func (op *OutPin) reset() error {
 	if op.Identifier == "" {
-		return
+		return nil
 	}
 
-	if op.PinIO == nil {
-		op.PinIO = gpioreg.ByName(op.Identifier)
-		if op.PinIO == nil {
-			log.Fatalf("No Pin for %v!\n", op.Identifier)
-		}
+	if op.Driver == nil {
+		op.Driver = &PeriphDriver{}
+	}
+	if err := op.Driver.Open(op.Identifier); err != nil {
+		return fmt.Errorf("opening %s: %w", op.Identifier, err)
 	}
 
-	op.off()
+	return op.off()
 }
 
 // Reset - Reset the output pins
-func (o *OutputControl) Reset() {
+func (o *OutputControl) Reset() error {
 	if o == nil {
-		return
+		return nil
 	}
 	if o.HeatOutput != nil {
-		o.HeatOutput.reset()
+		if err := o.HeatOutput.reset(); err != nil {
+			return err
+		}
 	}
 	if o.CoolOutput != nil {
-		o.CoolOutput.reset()
+		if err := o.CoolOutput.reset(); err != nil {
+			return err
+		}
+	}
+
+	if o.HeatPWM != nil {
+		if err := o.HeatPWM.reset(); err != nil {
+			fmt.Printf("HeatPWM %s unavailable (%v), falling back to software time-proportional control\n", o.HeatPWM.Identifier, err)
+			o.HeatPWM = nil
+		}
 	}
+	if o.CoolPWM != nil {
+		if err := o.CoolPWM.reset(); err != nil {
+			fmt.Printf("CoolPWM %s unavailable (%v), falling back to software time-proportional control\n", o.CoolPWM.Identifier, err)
+			o.CoolPWM = nil
+		}
+	}
+
+	return nil
 }
 
-// CalculateOutput - Turn on and off the output pin for this output control depending on the duty cycle
-func (o *OutputControl) CalculateOutput() {
-	cycleSeconds := math.Abs(float64(o.CycleTime*o.DutyCycle) / 100)
-
-	if o.DutyCycle == 0 {
-		o.HeatOutput.off()
-	} else if o.DutyCycle > 0 {
-		o.CoolOutput.off()
-
-		if o.HeatOutput.onTime != nil {
-			// it's on, do we need to turn it off?
-			changeAt := time.Since(*o.HeatOutput.onTime)
-			if changeAt.Seconds() > float64(cycleSeconds) {
-				fmt.Printf("Heat output turning off after %v seconds\n", changeAt.Seconds())
-				o.HeatOutput.off()
-			}
-		} else if o.HeatOutput.offTime != nil {
-			// it's off, do we need to turn it on?
-			changeAt := time.Since(*o.HeatOutput.offTime)
-			offSeconds := float64(o.CycleTime) - cycleSeconds
-			if changeAt.Seconds() >= offSeconds {
-				o.HeatOutput.on()
-			}
+// CalculateOutput - compute the next PID output for reading and drive the heat/cool
+// outputs for it via the time-proportional window, unless the interlock has paused us.
+func (o *OutputControl) CalculateOutput(reading Reading) error {
+	o.publish(Event{Timestamp: reading.Timestamp, Type: EventTemperature, Value: reading.Value})
+
+	heatWasOn := o.HeatOutput != nil && o.HeatOutput.onTime != nil
+	coolWasOn := o.CoolOutput != nil && o.CoolOutput.onTime != nil
+
+	var err error
+	if o.isPaused() {
+		if err = o.HeatOutput.off(); err == nil {
+			err = o.CoolOutput.off()
+		}
+		if err == nil {
+			err = o.applyPWM(0)
+		}
+	} else {
+		u := o.Controller.Compute(o.Setpoint, reading.Value, reading.Timestamp)
+		// A side driven by hardware PWM is held off in the time-proportional window.
+		windowU := u
+		if (u > 0 && o.HeatPWM != nil) || (u < 0 && o.CoolPWM != nil) {
+			windowU = 0
+		}
+		err = o.Output.Apply(windowU, reading.Timestamp)
+		if err == nil {
+			err = o.applyPWM(u)
+		}
+	}
+
+	o.publishTransitions(heatWasOn, coolWasOn, reading.Timestamp)
+	return err
+}
+
+// applyPWM drives HeatPWM/CoolPWM (whichever are configured) from controller
+// output u, in place of the time-proportional window TimeProportionalOutput
+// handles for HeatOutput/CoolOutput.
+func (o *OutputControl) applyPWM(u float64) error {
+	if o.HeatPWM != nil {
+		heatDuty := 0.0
+		if u > 0 {
+			heatDuty = u
+		}
+		if err := o.HeatPWM.SetDuty(heatDuty); err != nil {
+			return err
 		}
-	} else if o.DutyCycle < 0 {
-		o.HeatOutput.off()
+	}
+	if o.CoolPWM != nil {
+		coolDuty := 0.0
+		if u < 0 {
+			coolDuty = -u
+		}
+		if err := o.CoolPWM.SetDuty(coolDuty); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		if o.CoolOutput.onTime != nil {
-			// it's on, do we need to turn it off?
-			changeAt := time.Since(*o.CoolOutput.onTime)
-			if changeAt.Seconds() > float64(cycleSeconds) {
-				fmt.Printf("Cool output turning off after %v seconds\n", changeAt.Seconds())
-				o.CoolOutput.off()
+// publishTransitions compares the heat/cool outputs' prior on/off state against
+// their current state and publishes the corresponding Event for any change.
+func (o *OutputControl) publishTransitions(heatWasOn, coolWasOn bool, at time.Time) {
+	if o.HeatOutput != nil {
+		if heatIsOn := o.HeatOutput.onTime != nil; heatIsOn != heatWasOn {
+			evt := Event{Timestamp: at, Type: EventHeatOff}
+			if heatIsOn {
+				evt.Type = EventHeatOn
 			}
-		} else if o.CoolOutput.offTime != nil {
-			// it's off, do we need to turn it on?
-			changeAt := time.Since(*o.CoolOutput.offTime)
-			offSeconds := float64(o.CycleTime) - cycleSeconds
-			if changeAt.Seconds() >= offSeconds {
-				fmt.Printf("Cool output turning on after %v seconds\n", changeAt.Seconds())
-				o.CoolOutput.on()
+			o.publish(evt)
+		}
+	}
+	if o.CoolOutput != nil {
+		if coolIsOn := o.CoolOutput.onTime != nil; coolIsOn != coolWasOn {
+			evt := Event{Timestamp: at, Type: EventCoolOff}
+			if coolIsOn {
+				evt.Type = EventCoolOn
 			}
+			o.publish(evt)
 		}
 	}
 }
@@ -144,25 +283,39 @@ func (o *OutputControl) CalculateOutput() {
 // RunControl -> Run the output controller for a heating output
 func (o *OutputControl) RunControl() {
 	fmt.Println("Starting output control")
-	o.Reset()
-	duration, err := time.ParseDuration("10ms")
-	if err != nil {
-		log.Fatal(err)
+	o.Errors = make(chan error, 8)
+	o.Events = make(chan Event, 8)
+
+	if err := o.Reset(); err != nil {
+		o.sendError(err)
+		return
 	}
 
-	ticker := time.NewTicker(duration)
 	quit := make(chan struct{})
+	defer close(quit)
+
+	if o.DoorSensor != nil {
+		o.DoorSensor.Watch(quit)
+		go o.runInterlock(quit)
+	}
 
 	for {
 		select {
-		case <-ticker.C:
-			o.CalculateOutput()
+		case reading, ok := <-o.Readings:
+			if !ok {
+				fmt.Println("Stop")
+				return
+			}
+			if err := o.CalculateOutput(reading); err != nil {
+				o.sendError(err)
+			}
 		case <-quit:
-			ticker.Stop()
 			fmt.Println("Stop")
 			return
 		case <-Context.Done():
-			o.Reset()
+			if err := o.Reset(); err != nil {
+				o.sendError(err)
+			}
 			return
 		}
 	}
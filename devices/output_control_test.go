@@ -0,0 +1,83 @@
+package devices
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApplyPWMDrivesHeatAndCoolFromSignedOutput(t *testing.T) {
+	heatDriver := &mockPWMDriver{}
+	coolDriver := &mockPWMDriver{}
+	o := &OutputControl{
+		HeatPWM: newTestPWMPin(t, heatDriver),
+		CoolPWM: newTestPWMPin(t, coolDriver),
+	}
+
+	if err := o.applyPWM(0.4); err != nil {
+		t.Fatalf("applyPWM() error = %v", err)
+	}
+	if got := heatDriver.duties[len(heatDriver.duties)-1]; got != 0.4 {
+		t.Fatalf("heat duty = %v, want 0.4", got)
+	}
+	if got := coolDriver.duties[len(coolDriver.duties)-1]; got != 0 {
+		t.Fatalf("cool duty = %v, want 0 while heating", got)
+	}
+
+	if err := o.applyPWM(-0.7); err != nil {
+		t.Fatalf("applyPWM() error = %v", err)
+	}
+	if got := heatDriver.duties[len(heatDriver.duties)-1]; got != 0 {
+		t.Fatalf("heat duty = %v, want 0 while cooling", got)
+	}
+	if got := coolDriver.duties[len(coolDriver.duties)-1]; got != 0.7 {
+		t.Fatalf("cool duty = %v, want 0.7", got)
+	}
+}
+
+func TestCalculateOutputZeroesPWMWhilePaused(t *testing.T) {
+	heatDriver := &mockPWMDriver{}
+	o := &OutputControl{
+		HeatOutput: newMockOutPin(),
+		CoolOutput: newMockOutPin(),
+		HeatPWM:    newTestPWMPin(t, heatDriver),
+		Controller: &PIDController{Kp: 1},
+		Output:     &TimeProportionalOutput{Window: time.Second},
+		Setpoint:   10,
+		Events:     make(chan Event, 8),
+		Errors:     make(chan error, 8),
+	}
+
+	start := time.Now()
+	// Prime the controller, then drive a real non-zero duty so pausing has
+	// something to zero.
+	if err := o.CalculateOutput(Reading{Value: 0, Timestamp: start}); err != nil {
+		t.Fatalf("CalculateOutput() error = %v", err)
+	}
+	if err := o.CalculateOutput(Reading{Value: 0, Timestamp: start.Add(time.Second)}); err != nil {
+		t.Fatalf("CalculateOutput() error = %v", err)
+	}
+	if heatDriver.duties[len(heatDriver.duties)-1] == 0 {
+		t.Fatal("setup: expected a non-zero heat duty before pausing")
+	}
+
+	o.setPaused(true)
+	if err := o.CalculateOutput(Reading{Value: 0, Timestamp: start.Add(2 * time.Second)}); err != nil {
+		t.Fatalf("CalculateOutput() error = %v", err)
+	}
+	if got := heatDriver.duties[len(heatDriver.duties)-1]; got != 0 {
+		t.Fatalf("heat duty while paused = %v, want 0", got)
+	}
+}
+
+func TestOutputControlResetFallsBackWhenPWMDriverLacksCapability(t *testing.T) {
+	o := &OutputControl{
+		HeatPWM: &PWMPin{Identifier: "heat-pwm", Driver: &MockDriver{}},
+	}
+
+	if err := o.Reset(); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if o.HeatPWM != nil {
+		t.Fatal("Reset() should fall back to a nil HeatPWM when the driver doesn't support hardware PWM")
+	}
+}
@@ -0,0 +1,63 @@
+package devices
+
+import (
+	"fmt"
+	"time"
+
+	"periph.io/x/periph/conn/gpio"
+	"periph.io/x/periph/conn/gpio/gpioreg"
+	"periph.io/x/periph/conn/physic"
+)
+
+// PeriphDriver is the default PinDriver, backed by periph.io/x/periph's gpioreg.
+// It's the driver OutPin/InPin use when no Driver is configured.
+type PeriphDriver struct {
+	pin gpio.PinIO
+}
+
+// Open looks up name via gpioreg.
+func (p *PeriphDriver) Open(name string) error {
+	p.pin = gpioreg.ByName(name)
+	if p.pin == nil {
+		return fmt.Errorf("periph: no pin named %q", name)
+	}
+	return nil
+}
+
+// SetOutput drives the pin high or low.
+func (p *PeriphDriver) SetOutput(high bool) error {
+	level := gpio.Low
+	if high {
+		level = gpio.High
+	}
+	return p.pin.Out(level)
+}
+
+// SetInput configures the pin as a pulled-up, both-edges input.
+func (p *PeriphDriver) SetInput() error {
+	return p.pin.In(gpio.PullUp, gpio.BothEdges)
+}
+
+// WatchEdge waits for periph to notice an edge, then reports the resulting level.
+func (p *PeriphDriver) WatchEdge(timeout time.Duration) (bool, error) {
+	p.pin.WaitForEdge(timeout)
+	return p.Read()
+}
+
+// Read reports the pin's current level.
+func (p *PeriphDriver) Read() (bool, error) {
+	return p.pin.Read() == gpio.High, nil
+}
+
+// Close is a no-op: periph pins aren't owned/released by gpioreg lookups.
+func (p *PeriphDriver) Close() error {
+	return nil
+}
+
+// SetDuty drives the pin via periph's hardware-PWM support, satisfying PWMDriver
+// for pins whose PinOut.PWM is backed by real PWM hardware rather than periph's
+// software emulation.
+func (p *PeriphDriver) SetDuty(duty float64, freq time.Duration) error {
+	hz := physic.Frequency(float64(physic.Hertz) / freq.Seconds())
+	return p.pin.PWM(gpio.Duty(duty*float64(gpio.DutyMax)), hz)
+}
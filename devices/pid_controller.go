@@ -0,0 +1,83 @@
+package devices
+
+import "time"
+
+// PIDController is a standard PID controller with integral clamping,
+// derivative-on-measurement (so a setpoint change doesn't kick the derivative
+// term) and anti-windup via back-calculation. Compute produces a bipolar output
+// in [-1, 1]: positive drives heat, negative drives cool.
+type PIDController struct {
+	Kp, Ki, Kd float64
+
+	// IntegralLimit clamps the accumulated integral term to [-IntegralLimit, IntegralLimit].
+	// Zero disables clamping.
+	IntegralLimit float64
+	// BackCalculationGain pulls the integral back toward zero whenever Compute's
+	// output saturates at +-1, the standard anti-windup technique for a PID driving
+	// a bounded actuator.
+	BackCalculationGain float64
+
+	integral    float64
+	lastInput   float64
+	lastTime    time.Time
+	initialized bool
+}
+
+// Compute returns the controller output in [-1, 1] for setpoint and the measured
+// input at time now.
+func (p *PIDController) Compute(setpoint, input float64, now time.Time) float64 {
+	if !p.initialized {
+		p.lastInput = input
+		p.lastTime = now
+		p.initialized = true
+		return 0
+	}
+
+	dt := now.Sub(p.lastTime).Seconds()
+	if dt <= 0 {
+		dt = 1e-3
+	}
+
+	err := setpoint - input
+
+	p.integral += err * dt
+	if p.IntegralLimit > 0 {
+		switch {
+		case p.integral > p.IntegralLimit:
+			p.integral = p.IntegralLimit
+		case p.integral < -p.IntegralLimit:
+			p.integral = -p.IntegralLimit
+		}
+	}
+
+	derivative := -(input - p.lastInput) / dt
+
+	output := p.Kp*err + p.Ki*p.integral + p.Kd*derivative
+
+	clamped := output
+	switch {
+	case clamped > 1:
+		clamped = 1
+	case clamped < -1:
+		clamped = -1
+	}
+	if p.BackCalculationGain > 0 && clamped != output {
+		p.integral += p.BackCalculationGain * (clamped - output) * dt
+	}
+
+	p.lastInput = input
+	p.lastTime = now
+
+	return clamped
+}
+
+// Reset clears accumulated integral and derivative state, e.g. after Autotune
+// assigns new gains or the controller resumes from an interlock pause. It's a
+// no-op on a nil controller.
+func (p *PIDController) Reset() {
+	if p == nil {
+		return
+	}
+	p.integral = 0
+	p.initialized = false
+}
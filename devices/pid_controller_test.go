@@ -0,0 +1,74 @@
+package devices
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPIDControllerFirstComputeIsZero(t *testing.T) {
+	p := &PIDController{Kp: 1}
+	now := time.Now()
+
+	if got := p.Compute(20, 18, now); got != 0 {
+		t.Fatalf("first Compute() = %v, want 0 (no dt to integrate/differentiate over yet)", got)
+	}
+}
+
+func TestPIDControllerProportionalResponse(t *testing.T) {
+	p := &PIDController{Kp: 0.1}
+	now := time.Now()
+
+	p.Compute(20, 18, now) // prime lastInput/lastTime
+
+	got := p.Compute(20, 18, now.Add(time.Second))
+	want := 0.2 // Kp * error = 0.1 * 2
+	if math.Abs(got-want) > 1e-9 {
+		t.Fatalf("Compute() = %v, want %v", got, want)
+	}
+}
+
+func TestPIDControllerOutputIsClamped(t *testing.T) {
+	p := &PIDController{Kp: 10}
+	now := time.Now()
+
+	p.Compute(50, 0, now)
+	got := p.Compute(50, 0, now.Add(time.Second))
+
+	if got != 1 {
+		t.Fatalf("Compute() = %v, want output clamped to 1", got)
+	}
+}
+
+func TestPIDControllerIntegralLimit(t *testing.T) {
+	p := &PIDController{Ki: 1, IntegralLimit: 2}
+	now := time.Now()
+
+	p.Compute(10, 0, now)
+	for i := 1; i <= 10; i++ {
+		p.Compute(10, 0, now.Add(time.Duration(i)*time.Second))
+	}
+
+	if p.integral > 2 {
+		t.Fatalf("integral = %v, want clamped to IntegralLimit (2)", p.integral)
+	}
+}
+
+func TestPIDControllerReset(t *testing.T) {
+	p := &PIDController{Kp: 1, Ki: 1}
+	now := time.Now()
+
+	p.Compute(10, 0, now)
+	p.Compute(10, 0, now.Add(time.Second))
+
+	p.Reset()
+
+	if p.integral != 0 || p.initialized {
+		t.Fatalf("Reset() left integral=%v initialized=%v, want zeroed", p.integral, p.initialized)
+	}
+
+	// Reset should make the next Compute behave like a fresh controller: zero output.
+	if got := p.Compute(10, 0, now.Add(2*time.Second)); got != 0 {
+		t.Fatalf("Compute() after Reset() = %v, want 0", got)
+	}
+}
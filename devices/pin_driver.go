@@ -0,0 +1,19 @@
+package devices
+
+import "time"
+
+// PinDriver abstracts a single GPIO pin so OutPin/InPin can run against real
+// hardware or a MockDriver in tests, instead of being hard-wired to one backend.
+// Open binds the driver to a pin by name/identifier; the remaining methods then
+// act on that pin.
+type PinDriver interface {
+	Open(name string) error
+	SetOutput(high bool) error
+	SetInput() error
+	// WatchEdge blocks until an edge is observed or timeout elapses, then reports
+	// the level read afterwards. Drivers that can't watch for edges should sleep
+	// for timeout and fall back to a plain Read, letting callers poll instead.
+	WatchEdge(timeout time.Duration) (bool, error)
+	Read() (bool, error)
+	Close() error
+}
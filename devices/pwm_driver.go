@@ -0,0 +1,12 @@
+package devices
+
+import "time"
+
+// PWMDriver is implemented by PinDriver backends capable of hardware PWM output.
+// It's an optional capability, checked with a type assertion, so a PinDriver that
+// doesn't implement it (most don't) just can't back a PWMPin.
+type PWMDriver interface {
+	// SetDuty drives the pin at duty (in [0, 1]) and freq. A duty of 0 turns the
+	// output fully off.
+	SetDuty(duty float64, freq time.Duration) error
+}
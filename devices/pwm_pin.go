@@ -0,0 +1,93 @@
+package devices
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PWMPin drives an output in hardware-PWM mode (kHz-range switching) rather than
+// the second-scale on/off windows OutPin/TimeProportionalOutput use - suited to
+// DC fans, pumps, and PWM-capable SSRs where bit-banging would be audible or
+// wear the actuator.
+type PWMPin struct {
+	gorm.Model
+
+	Identifier   string
+	FriendlyName string
+	Driver       PinDriver `gorm:"-"`
+
+	Frequency time.Duration `gorm:"-"` // PWM period, e.g. time.Second/1000 for 1kHz
+	MinDuty   float64       `gorm:"-"` // floor in [0,1] applied to any non-zero duty
+	MaxDuty   float64       `gorm:"-"` // ceiling in [0,1], 0 means unlimited
+
+	// Kickstart, when true, drives the output at 100% for KickstartDuration
+	// whenever duty rises from 0, to overcome fan/pump stiction.
+	Kickstart         bool          `gorm:"-"`
+	KickstartDuration time.Duration `gorm:"-"`
+
+	pwm      PWMDriver
+	lastDuty float64
+}
+
+func (p *PWMPin) reset() error {
+	if p.Identifier == "" {
+		return nil
+	}
+
+	if p.Driver == nil {
+		p.Driver = &PeriphDriver{}
+	}
+	if err := p.Driver.Open(p.Identifier); err != nil {
+		return fmt.Errorf("opening %s: %w", p.Identifier, err)
+	}
+
+	pwm, ok := p.Driver.(PWMDriver)
+	if !ok {
+		return fmt.Errorf("%s: driver %T does not support hardware PWM", p.Identifier, p.Driver)
+	}
+	p.pwm = pwm
+	p.lastDuty = 0
+
+	return p.SetDuty(0)
+}
+
+// SetDuty sets the output's duty cycle to duty (clamped to [0,1], then to
+// [MinDuty, MaxDuty] unless it's exactly 0), kickstarting at 100% first if
+// configured and duty is rising from 0.
+func (p *PWMPin) SetDuty(duty float64) error {
+	if p.pwm == nil {
+		return fmt.Errorf("%s: not initialised", p.Identifier)
+	}
+
+	if duty < 0 {
+		duty = 0
+	}
+	if duty > 1 {
+		duty = 1
+	}
+
+	clamped := duty
+	if clamped > 0 {
+		if p.MaxDuty > 0 && clamped > p.MaxDuty {
+			clamped = p.MaxDuty
+		}
+		if clamped < p.MinDuty {
+			clamped = p.MinDuty
+		}
+	}
+
+	if p.Kickstart && p.lastDuty == 0 && clamped > 0 {
+		if err := p.pwm.SetDuty(1, p.Frequency); err != nil {
+			return fmt.Errorf("%s: kickstart pulse: %w", p.Identifier, err)
+		}
+		time.Sleep(p.KickstartDuration)
+	}
+
+	if err := p.pwm.SetDuty(clamped, p.Frequency); err != nil {
+		return fmt.Errorf("%s: setting duty %.2f: %w", p.Identifier, clamped, err)
+	}
+	p.lastDuty = clamped
+	return nil
+}
@@ -0,0 +1,106 @@
+package devices
+
+import (
+	"testing"
+	"time"
+)
+
+// mockPWMDriver is a PinDriver that also implements PWMDriver, recording every
+// SetDuty call so PWMPin's clamping/kickstart logic can be asserted
+// deterministically off-Pi.
+type mockPWMDriver struct {
+	MockDriver
+	duties []float64
+}
+
+func (m *mockPWMDriver) SetDuty(duty float64, freq time.Duration) error {
+	m.duties = append(m.duties, duty)
+	return nil
+}
+
+func newTestPWMPin(t *testing.T, driver PinDriver) *PWMPin {
+	t.Helper()
+	p := &PWMPin{Identifier: "pwm", Driver: driver}
+	if err := p.reset(); err != nil {
+		t.Fatalf("reset() error = %v", err)
+	}
+	return p
+}
+
+func TestPWMPinSetDutyClampsToMinMax(t *testing.T) {
+	driver := &mockPWMDriver{}
+	p := newTestPWMPin(t, driver)
+	p.MinDuty = 0.2
+	p.MaxDuty = 0.8
+
+	if err := p.SetDuty(0.05); err != nil {
+		t.Fatalf("SetDuty() error = %v", err)
+	}
+	if got := driver.duties[len(driver.duties)-1]; got != 0.2 {
+		t.Fatalf("duty = %v, want clamped up to MinDuty (0.2)", got)
+	}
+
+	if err := p.SetDuty(0.95); err != nil {
+		t.Fatalf("SetDuty() error = %v", err)
+	}
+	if got := driver.duties[len(driver.duties)-1]; got != 0.8 {
+		t.Fatalf("duty = %v, want clamped down to MaxDuty (0.8)", got)
+	}
+
+	if err := p.SetDuty(0); err != nil {
+		t.Fatalf("SetDuty() error = %v", err)
+	}
+	if got := driver.duties[len(driver.duties)-1]; got != 0 {
+		t.Fatalf("duty = %v, want 0 to bypass MinDuty", got)
+	}
+}
+
+func TestPWMPinKickstartsOnRiseFromZero(t *testing.T) {
+	driver := &mockPWMDriver{}
+	p := newTestPWMPin(t, driver)
+	p.Kickstart = true
+	p.KickstartDuration = time.Millisecond
+
+	if err := p.SetDuty(0.5); err != nil {
+		t.Fatalf("SetDuty() error = %v", err)
+	}
+
+	// reset() already issued one SetDuty(0); kickstart should insert a 100%
+	// pulse ahead of the requested 0.5 duty.
+	if len(driver.duties) != 3 {
+		t.Fatalf("got %d SetDuty calls, want 3 (reset's 0, the kickstart pulse, then 0.5)", len(driver.duties))
+	}
+	if driver.duties[1] != 1 {
+		t.Fatalf("kickstart duty = %v, want 1", driver.duties[1])
+	}
+	if driver.duties[2] != 0.5 {
+		t.Fatalf("final duty = %v, want 0.5", driver.duties[2])
+	}
+}
+
+func TestPWMPinKickstartNotRepeatedWhileAlreadyDriven(t *testing.T) {
+	driver := &mockPWMDriver{}
+	p := newTestPWMPin(t, driver)
+	p.Kickstart = true
+	p.KickstartDuration = time.Millisecond
+
+	if err := p.SetDuty(0.5); err != nil {
+		t.Fatalf("SetDuty() error = %v", err)
+	}
+	calls := len(driver.duties)
+
+	if err := p.SetDuty(0.6); err != nil {
+		t.Fatalf("SetDuty() error = %v", err)
+	}
+	if len(driver.duties) != calls+1 {
+		t.Fatalf("got %d SetDuty calls for a non-zero-to-non-zero change, want 1 (no repeated kickstart)", len(driver.duties)-calls)
+	}
+}
+
+func TestPWMPinResetFallsBackWhenDriverLacksPWM(t *testing.T) {
+	p := &PWMPin{Identifier: "pwm", Driver: &MockDriver{}}
+
+	if err := p.reset(); err == nil {
+		t.Fatal("reset() with a non-PWMDriver should error so OutputControl.Reset can fall back to software control")
+	}
+}
@@ -0,0 +1,108 @@
+package devices
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SysfsPWMDriver is a PWMDriver fallback for hardware-PWM output via the Linux
+// sysfs pwmchip interface, for boards/pins periph's gpio.PinOut.PWM doesn't
+// cover. The identifier passed to Open is "<chip>:<channel>", e.g. "0:1" for
+// /sys/class/pwm/pwmchip0's channel 1.
+type SysfsPWMDriver struct {
+	chip, channel int
+	periodNs      int64
+	exported      bool
+}
+
+// Open exports the pwmchip channel encoded in name.
+func (s *SysfsPWMDriver) Open(name string) error {
+	parts := strings.SplitN(name, ":", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("sysfs-pwm: identifier must be \"<chip>:<channel>\", got %q", name)
+	}
+
+	chip, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return fmt.Errorf("sysfs-pwm: invalid chip %q: %w", parts[0], err)
+	}
+	channel, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return fmt.Errorf("sysfs-pwm: invalid channel %q: %w", parts[1], err)
+	}
+	s.chip, s.channel = chip, channel
+
+	if err := os.WriteFile(s.chipPath("export"), []byte(strconv.Itoa(channel)), 0644); err != nil && !os.IsExist(err) {
+		return fmt.Errorf("sysfs-pwm: exporting channel %d: %w", channel, err)
+	}
+	s.exported = true
+	return nil
+}
+
+func (s *SysfsPWMDriver) chipPath(file string) string {
+	return filepath.Join("/sys/class/pwm", fmt.Sprintf("pwmchip%d", s.chip), file)
+}
+
+func (s *SysfsPWMDriver) channelPath(file string) string {
+	return filepath.Join("/sys/class/pwm", fmt.Sprintf("pwmchip%d", s.chip), fmt.Sprintf("pwm%d", s.channel), file)
+}
+
+// SetOutput maps a plain on/off request to a fully on/off duty, so
+// SysfsPWMDriver can also back a non-PWM OutPin if needed.
+func (s *SysfsPWMDriver) SetOutput(high bool) error {
+	duty := 0.0
+	if high {
+		duty = 1
+	}
+	return s.SetDuty(duty, time.Duration(s.periodNs)*time.Nanosecond)
+}
+
+// SetInput always fails: a pwmchip channel is output-only.
+func (s *SysfsPWMDriver) SetInput() error {
+	return fmt.Errorf("sysfs-pwm: channel %d:%d is output-only", s.chip, s.channel)
+}
+
+// WatchEdge always fails: a pwmchip channel has no edge/interrupt support.
+func (s *SysfsPWMDriver) WatchEdge(timeout time.Duration) (bool, error) {
+	return false, fmt.Errorf("sysfs-pwm: channel %d:%d does not support edge watching", s.chip, s.channel)
+}
+
+// Read always fails: a pwmchip channel has no readable input state.
+func (s *SysfsPWMDriver) Read() (bool, error) {
+	return false, fmt.Errorf("sysfs-pwm: channel %d:%d does not support reading", s.chip, s.channel)
+}
+
+// Close unexports the channel.
+func (s *SysfsPWMDriver) Close() error {
+	if !s.exported {
+		return nil
+	}
+	return os.WriteFile(s.chipPath("unexport"), []byte(strconv.Itoa(s.channel)), 0644)
+}
+
+// SetDuty writes freq's period and the corresponding duty_cycle, then enables
+// the channel, satisfying PWMDriver.
+func (s *SysfsPWMDriver) SetDuty(duty float64, freq time.Duration) error {
+	if duty < 0 {
+		duty = 0
+	}
+	if duty > 1 {
+		duty = 1
+	}
+
+	s.periodNs = freq.Nanoseconds()
+	if err := os.WriteFile(s.channelPath("period"), []byte(strconv.FormatInt(s.periodNs, 10)), 0644); err != nil {
+		return fmt.Errorf("sysfs-pwm: setting period: %w", err)
+	}
+
+	dutyNs := int64(duty * float64(s.periodNs))
+	if err := os.WriteFile(s.channelPath("duty_cycle"), []byte(strconv.FormatInt(dutyNs, 10)), 0644); err != nil {
+		return fmt.Errorf("sysfs-pwm: setting duty_cycle: %w", err)
+	}
+
+	return os.WriteFile(s.channelPath("enable"), []byte("1"), 0644)
+}
@@ -0,0 +1,92 @@
+package devices
+
+import (
+	"math"
+	"time"
+)
+
+// OutputTiming holds the minimum on/off durations and a re-energize cooldown a
+// given output must respect. A compressor's cool output typically needs all
+// three to avoid short-cycling; a resistive heat output usually needs none.
+type OutputTiming struct {
+	MinOn    time.Duration
+	MinOff   time.Duration
+	Cooldown time.Duration
+}
+
+// TimeProportionalOutput drives HeatOutput/CoolOutput from a bipolar controller
+// output u in [-1, 1] using the classic Beer/Ferment time-proportional pattern:
+// within each Window, the selected output is held on for |u|*Window and off for
+// the remainder.
+type TimeProportionalOutput struct {
+	HeatOutput *OutPin
+	CoolOutput *OutPin
+
+	Window time.Duration
+	// DeadBand is the |u| below which neither output is driven.
+	DeadBand float64
+
+	HeatTiming OutputTiming
+	CoolTiming OutputTiming
+
+	windowStart time.Time
+}
+
+// Apply turns the heat/cool outputs on or off for this tick given controller
+// output u at time now.
+func (t *TimeProportionalOutput) Apply(u float64, now time.Time) error {
+	if t.windowStart.IsZero() || now.Sub(t.windowStart) >= t.Window {
+		t.windowStart = now
+	}
+	elapsed := now.Sub(t.windowStart)
+
+	if math.Abs(u) < t.DeadBand {
+		if err := t.drive(t.HeatOutput, false, t.HeatTiming, now); err != nil {
+			return err
+		}
+		return t.drive(t.CoolOutput, false, t.CoolTiming, now)
+	}
+
+	onFor := time.Duration(math.Abs(u) * float64(t.Window))
+
+	if u > 0 {
+		if err := t.drive(t.CoolOutput, false, t.CoolTiming, now); err != nil {
+			return err
+		}
+		return t.drive(t.HeatOutput, elapsed < onFor, t.HeatTiming, now)
+	}
+
+	if err := t.drive(t.HeatOutput, false, t.HeatTiming, now); err != nil {
+		return err
+	}
+	return t.drive(t.CoolOutput, elapsed < onFor, t.CoolTiming, now)
+}
+
+// drive moves op toward the wanted on/off state, refusing transitions that would
+// violate timing's min-on, min-off or cooldown constraints.
+func (t *TimeProportionalOutput) drive(op *OutPin, want bool, timing OutputTiming, now time.Time) error {
+	if op == nil {
+		return nil
+	}
+
+	if want {
+		if op.onTime != nil {
+			return nil
+		}
+		if op.offTime != nil {
+			sinceOff := now.Sub(*op.offTime)
+			if sinceOff < timing.MinOff || sinceOff < timing.Cooldown {
+				return nil
+			}
+		}
+		return op.on()
+	}
+
+	if op.offTime != nil {
+		return nil
+	}
+	if op.onTime != nil && now.Sub(*op.onTime) < timing.MinOn {
+		return nil
+	}
+	return op.off()
+}
@@ -0,0 +1,99 @@
+package devices
+
+import (
+	"testing"
+	"time"
+)
+
+func newMockOutPin() *OutPin {
+	return &OutPin{Identifier: "mock", Driver: &MockDriver{}}
+}
+
+func TestTimeProportionalOutputAppliesProportionalOnTime(t *testing.T) {
+	heat := newMockOutPin()
+	tpo := &TimeProportionalOutput{HeatOutput: heat, Window: 10 * time.Second}
+	start := time.Now()
+
+	if err := tpo.Apply(0.5, start); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if heat.onTime == nil {
+		t.Fatal("heat output should be on for the first half of the window")
+	}
+
+	// 6s into a 10s window with u=0.5 (5s on-time): past onFor, should turn off.
+	if err := tpo.Apply(0.5, start.Add(6*time.Second)); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if heat.onTime != nil {
+		t.Fatal("heat output should be off once elapsed exceeds |u|*Window")
+	}
+}
+
+func TestTimeProportionalOutputDeadBand(t *testing.T) {
+	heat := newMockOutPin()
+	cool := newMockOutPin()
+	tpo := &TimeProportionalOutput{HeatOutput: heat, CoolOutput: cool, Window: time.Second, DeadBand: 0.1}
+	now := time.Now()
+
+	if err := tpo.Apply(0.05, now); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if heat.onTime != nil || cool.onTime != nil {
+		t.Fatal("a |u| inside the dead band should drive neither output")
+	}
+}
+
+func TestTimeProportionalOutputOppositeSideIsForcedOff(t *testing.T) {
+	heat := newMockOutPin()
+	cool := newMockOutPin()
+	tpo := &TimeProportionalOutput{HeatOutput: heat, CoolOutput: cool, Window: 10 * time.Second}
+	now := time.Now()
+
+	if err := tpo.Apply(-0.5, now); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if heat.onTime != nil {
+		t.Fatal("heat output must stay off while u is negative (cooling)")
+	}
+	if cool.onTime == nil {
+		t.Fatal("cool output should be on while u is negative")
+	}
+}
+
+func TestDriveRespectsMinOn(t *testing.T) {
+	tpo := &TimeProportionalOutput{}
+	op := newMockOutPin()
+	now := time.Now()
+
+	if err := op.on(); err != nil {
+		t.Fatalf("on() error = %v", err)
+	}
+
+	// Asking to turn off 1s after it turned on, with a 5s MinOn, must be refused.
+	if err := tpo.drive(op, false, OutputTiming{MinOn: 5 * time.Second}, now.Add(time.Second)); err != nil {
+		t.Fatalf("drive() error = %v", err)
+	}
+	if op.onTime == nil {
+		t.Fatal("drive() turned the output off before MinOn elapsed")
+	}
+}
+
+func TestDriveRespectsCooldown(t *testing.T) {
+	tpo := &TimeProportionalOutput{}
+	op := newMockOutPin()
+	now := time.Now()
+
+	if err := op.off(); err != nil {
+		t.Fatalf("off() error = %v", err)
+	}
+
+	// Asking to re-energize 1s after it turned off, with a 30s Cooldown (e.g. a
+	// compressor's cool output), must be refused.
+	if err := tpo.drive(op, true, OutputTiming{Cooldown: 30 * time.Second}, now.Add(time.Second)); err != nil {
+		t.Fatalf("drive() error = %v", err)
+	}
+	if op.onTime != nil {
+		t.Fatal("drive() re-energized the output before Cooldown elapsed")
+	}
+}